@@ -5,74 +5,139 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cli/cli/v2/git"
 	"github.com/cli/cli/v2/internal/config"
+	"github.com/cli/cli/v2/internal/ghinstance"
 	"github.com/cli/cli/v2/internal/ghrepo"
-	"github.com/cli/cli/v2/pkg/cmd/repo/view"
+	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/cli/cli/v2/pkg/extensions"
 	"github.com/cli/cli/v2/pkg/search"
+	"github.com/sahilm/fuzzy"
 	"github.com/spf13/cobra"
 )
 
 var appStyle = lipgloss.NewStyle().Padding(1, 2)
 var sidebarStyle = lipgloss.NewStyle()
+var errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4242"))
 
 type readmeGetter interface {
 	Get(string) (string, error)
 }
 
+// cachingReadmeGetter serves READMEs from an in-memory cache for the
+// lifetime of the TUI session, falling back to a disk-backed cache (which
+// survives across `gh ext browse` invocations) and finally a conditional
+// network fetch, made only once the disk copy is stale (or on refresh), that
+// costs nothing when the server reports the copy unchanged.
 type cachingReadmeGetter struct {
-	client *http.Client
-	cache  map[string]string
+	client  *http.Client
+	host    string
+	refresh bool
+
+	// mu guards cache, since the prefetch worker pool calls Get from
+	// multiple goroutines concurrently.
+	mu    sync.Mutex
+	cache map[string]string
+	disk  *diskReadmeCache
 }
 
-func newReadmeGetter(client *http.Client) readmeGetter {
+func newReadmeGetter(client *http.Client, host, cacheDir string, refresh bool) readmeGetter {
 	return &cachingReadmeGetter{
-		client: client,
-		cache:  map[string]string{},
+		client:  client,
+		host:    host,
+		cache:   map[string]string{},
+		disk:    newDiskReadmeCache(filepath.Join(cacheDir, "extensions", "readme-cache"), defaultReadmeCacheTTL),
+		refresh: refresh,
 	}
 }
 
 func (g *cachingReadmeGetter) Get(repoFullName string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if readme, ok := g.cache[repoFullName]; ok {
 		return readme, nil
 	}
-	repo, err := ghrepo.FromFullName(repoFullName)
-	readme, err := view.RepositoryReadme(g.client, repo, "")
+
+	content, meta, fresh, cached := g.disk.Load(repoFullName)
+	if cached && fresh && !g.refresh {
+		g.cache[repoFullName] = content
+		return content, nil
+	}
+	if !cached {
+		meta = readmeCacheMeta{}
+	}
+
+	url := ghinstance.RESTPrefix(g.host) + fmt.Sprintf("repos/%s/readme", repoFullName)
+	body, newMeta, notModified, err := conditionalFetch(g.client, url, meta)
 	if err != nil {
+		if cached {
+			// Serve the stale copy rather than blanking out the sidebar.
+			g.cache[repoFullName] = content
+			return content, nil
+		}
 		return "", err
 	}
-	g.cache[repoFullName] = readme.Content
-	return readme.Content, nil
+	if notModified {
+		meta.FetchedAt = newMeta.FetchedAt
+		_ = g.disk.Save(repoFullName, content, meta)
+		g.cache[repoFullName] = content
+		return content, nil
+	}
+
+	content = string(body)
+	_ = g.disk.Save(repoFullName, content, newMeta)
+	g.cache[repoFullName] = content
+	return content, nil
 }
 
+// defaultReadmeWidth is the glamour word-wrap width used before the first
+// tea.WindowSizeMsg arrives.
+const defaultReadmeWidth = 100
+
 type uiModel struct {
-	sidebar      sidebarModel
-	extList      extListModel
-	logger       *log.Logger
-	readmeGetter readmeGetter
+	sidebar    sidebarModel
+	extList    extListModel
+	logger     *log.Logger
+	prefetcher *readmePrefetcher
+
+	// rendered caches glamour output keyed by readmeJobKey(fullName, width)
+	// so a terminal resize doesn't force every README to re-render, and so
+	// prefetched neighbors are ready the moment they're selected.
+	rendered map[string]string
+
+	selected string
+	width    int
 }
 
 func newUIModel(opts extBrowseOpts, extEntries []extEntry) uiModel {
 	return uiModel{
-		extList:      newExtListModel(opts, extEntries),
-		sidebar:      newSidebarModel(opts.logger),
-		logger:       opts.logger,
-		readmeGetter: opts.rg,
+		extList:    newExtListModel(opts, extEntries),
+		sidebar:    newSidebarModel(opts.logger),
+		logger:     opts.logger,
+		prefetcher: newReadmePrefetcher(opts.rg, opts.logger),
+		rendered:   map[string]string{},
+		width:      defaultReadmeWidth,
 	}
 }
 
 func (m uiModel) Init() tea.Cmd {
 	// TODO the docs say not to do this but the example code in bubbles does:
-	return tea.EnterAltScreen
+	return tea.Batch(tea.EnterAltScreen, m.prefetcher.waitForResult(), m.sidebar.Init(), m.extList.manager.Init())
 }
 
 func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -82,6 +147,41 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var newModel tea.Model
 
+	if pm, ok := msg.(progressMsg); ok {
+		if pm.err != nil {
+			m.sidebar.Footer = errorStyle.Render(pm.err.Error())
+		} else if pm.done {
+			// Clear a previous failure's banner once an operation succeeds;
+			// otherwise it lingers in the sidebar forever.
+			m.sidebar.Footer = ""
+		}
+	}
+
+	if wsm, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = wsm.Width - 100
+		if m.width < 20 {
+			m.width = 20
+		}
+	}
+
+	if rm, ok := msg.(readmeReadyMsg); ok {
+		key := readmeJobKey(rm.fullName, rm.width)
+		if rm.err != nil {
+			m.logger.Println(rm.err.Error())
+			m.rendered[key] = "could not fetch readme x_x"
+		} else {
+			m.rendered[key] = rm.rendered
+			// Cache the raw markdown on the list item too, so `/readme`
+			// filtering has something to match against.
+			m.extList.SetReadme(rm.fullName, rm.readme)
+		}
+		if rm.fullName == m.selected && rm.width == m.width {
+			m.sidebar.Content = m.rendered[key]
+			m.sidebar.Loading = false
+		}
+		cmds = append(cmds, m.prefetcher.waitForResult())
+	}
+
 	newModel, cmd = m.extList.Update(msg)
 	cmds = append(cmds, cmd)
 	m.extList = newModel.(extListModel)
@@ -90,27 +190,21 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if item != nil {
 		ee := item.(extEntry)
-		readme, err := m.readmeGetter.Get(ee.FullName)
-		if err != nil {
-			ee.Readme = "could not fetch readme x_x"
-			m.logger.Println(err.Error())
-		} else {
-			renderer, err := glamour.NewTermRenderer(
-				glamour.WithAutoStyle(),
-				glamour.WithWordWrap(100),
-			)
-			if err != nil {
-				ee.Readme = "could not render readme x_x"
-				m.logger.Println(err.Error())
+		if ee.FullName != m.selected {
+			m.selected = ee.FullName
+			if cached, ok := m.rendered[readmeJobKey(ee.FullName, m.width)]; ok {
+				m.sidebar.Content = cached
+				m.sidebar.Loading = false
 			} else {
-				ee.Readme, err = renderer.Render(readme)
-				if err != nil {
-					ee.Readme = "could not render readme x_x"
-					m.logger.Println(err.Error())
-				}
+				m.sidebar.Loading = true
+				m.prefetcher.enqueue(ee.FullName, m.width)
+			}
+		}
+		for _, neighbor := range m.extList.Neighbors(readmePrefetchRadius) {
+			if _, ok := m.rendered[readmeJobKey(neighbor.FullName, m.width)]; !ok {
+				m.prefetcher.enqueue(neighbor.FullName, m.width)
 			}
 		}
-		m.sidebar.Content = ee.Readme
 	}
 
 	newModel, cmd = m.sidebar.Update(msg)
@@ -124,23 +218,85 @@ func (m uiModel) View() string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, m.extList.View(), m.sidebar.View())
 }
 
+// progressMsg reports incremental status for an in-flight install or remove
+// operation so managerModel can animate a spinner and extListModel can
+// reconcile the affected extEntry once the operation finishes.
+type progressMsg struct {
+	repoFullName string
+	action       string // "install" or "remove"
+	phase        string
+	line         string
+	err          error
+	done         bool
+
+	// ch is the channel this message arrived on, kept so Update can
+	// re-issue waitForProgress and keep draining it until done.
+	ch chan progressMsg
+}
+
 type managerModel struct {
 	logger  *log.Logger
 	content string
 	spinner spinner.Model
+	em      extensions.ExtensionManager
+
+	busy   bool
+	action string // "install" or "remove"
+	target string // repoFullName of the extension currently being acted on
+	phase  string
+	err    error
 }
 
-func newManagerModel(l *log.Logger) managerModel {
+func newManagerModel(l *log.Logger, em extensions.ExtensionManager) managerModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	return managerModel{
 		logger:  l,
 		spinner: s,
+		em:      em,
 	}
 }
 
-func (m managerModel) Install(repoFullName string) {
-	// TODO
+// Install kicks off em.Install for ee in the background and streams its
+// progress back as progressMsg values.
+func (m managerModel) Install(ee extEntry) tea.Cmd {
+	// Buffered so the goroutine never blocks waiting for Update to catch up.
+	ch := make(chan progressMsg, 4)
+	go func() {
+		ch <- progressMsg{repoFullName: ee.FullName, action: "install", phase: "installing", ch: ch}
+		repo, err := ghrepo.FromFullName(ee.FullName)
+		if err != nil {
+			ch <- progressMsg{repoFullName: ee.FullName, action: "install", err: err, done: true, ch: ch}
+			return
+		}
+		if err := m.em.Install(repo, ""); err != nil {
+			ch <- progressMsg{repoFullName: ee.FullName, action: "install", err: err, done: true, ch: ch}
+			return
+		}
+		ch <- progressMsg{repoFullName: ee.FullName, action: "install", phase: "installed", done: true, ch: ch}
+	}()
+	return waitForProgress(ch)
+}
+
+// Remove kicks off em.Remove for ee in the background and streams its
+// progress back as progressMsg values.
+func (m managerModel) Remove(ee extEntry) tea.Cmd {
+	ch := make(chan progressMsg, 4)
+	go func() {
+		ch <- progressMsg{repoFullName: ee.FullName, action: "remove", phase: "removing", ch: ch}
+		if err := m.em.Remove(ee.Name); err != nil {
+			ch <- progressMsg{repoFullName: ee.FullName, action: "remove", err: err, done: true, ch: ch}
+			return
+		}
+		ch <- progressMsg{repoFullName: ee.FullName, action: "remove", phase: "removed", done: true, ch: ch}
+	}()
+	return waitForProgress(ch)
+}
+
+func waitForProgress(ch chan progressMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
 }
 
 func (m managerModel) Init() tea.Cmd {
@@ -151,36 +307,65 @@ func (m managerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.logger.Printf("%#v", msg)
 	var cmd tea.Cmd
 	m.spinner, cmd = m.spinner.Update(msg)
+
+	if pm, ok := msg.(progressMsg); ok {
+		m.err = pm.err
+		m.phase = pm.phase
+		m.target = pm.repoFullName
+		m.action = pm.action
+		m.busy = !pm.done
+		if !pm.done {
+			// Keep draining this operation's channel until it reports done;
+			// otherwise its final message would block forever unread.
+			cmd = tea.Batch(cmd, waitForProgress(pm.ch))
+		}
+	}
+
 	return m, cmd
 }
 
 func (m managerModel) View() string {
-	// TODO punting on spinner for now; it just would not animate
-	//return fmt.Sprintf("%s installing...", m.spinner.View())
+	if m.err != nil {
+		return fmt.Sprintf("failed to %s %s: %s", m.action, m.target, m.err.Error())
+	}
+	if m.busy {
+		return fmt.Sprintf("%s %s %s...", m.spinner.View(), m.phase, m.target)
+	}
+	if m.phase != "" {
+		return fmt.Sprintf("%s %s", m.target, m.phase)
+	}
 	return m.content
 }
 
 type sidebarModel struct {
 	logger   *log.Logger
 	Content  string
+	Footer   string
+	Loading  bool
+	spinner  spinner.Model
 	viewport viewport.Model
 	ready    bool
 }
 
 func newSidebarModel(l *log.Logger) sidebarModel {
-	// TODO
+	s := spinner.New()
+	s.Spinner = spinner.Dot
 	return sidebarModel{
-		logger: l,
+		logger:  l,
+		spinner: s,
 	}
 }
 
 func (m sidebarModel) Init() tea.Cmd {
-	return nil
+	return m.spinner.Tick
 }
 
 func (m sidebarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.logger.Printf("%#v", msg)
-	// TODO
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		if !m.ready {
@@ -195,26 +380,43 @@ func (m sidebarModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.viewport.SetContent(m.Content)
 	}
 
-	newvp, cmd := m.viewport.Update(msg)
+	newvp, vpCmd := m.viewport.Update(msg)
 	m.viewport = newvp
-	return m, cmd
+	return m, tea.Batch(cmd, vpCmd)
 }
 
 func (m sidebarModel) View() string {
-	return m.viewport.View()
+	body := m.viewport.View()
+	if m.Loading {
+		body = fmt.Sprintf("%s fetching readme...", m.spinner.View())
+	}
+	if m.Footer != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, body, m.Footer)
+	}
+	return body
 	//return sidebarStyle.Render(m.viewport.View())
 }
 
 type extEntry struct {
-	URL         string
-	Owner       string
-	Name        string
-	FullName    string
-	Readme      string
-	Stars       int
-	Installed   bool
-	Official    bool
-	description string
+	URL      string
+	Owner    string
+	Name     string
+	FullName string
+	Readme   string
+	// ReadmeFetched reports whether a README fetch has succeeded for this
+	// entry, as opposed to Readme just being empty because nothing has
+	// requested it yet. A failed fetch, including one for a repository with
+	// no README, leaves it false. The "has readme" filter uses this to avoid
+	// hiding entries it simply hasn't looked at, so it hides only entries
+	// whose successfully fetched README is empty.
+	ReadmeFetched bool
+	Stars         int
+	Installed     bool
+	Official      bool
+	Topics        []string
+	UpdatedAt     time.Time
+	Language      string
+	description   string
 }
 
 func (e extEntry) Title() string {
@@ -236,12 +438,126 @@ func (e extEntry) Title() string {
 }
 
 func (e extEntry) Description() string { return e.description }
-func (e extEntry) FilterValue() string { return e.Title() }
+
+// filterFieldSep separates the fields packed into FilterValue() so extFilter
+// can recover them from the flat string the list package hands it.
+const filterFieldSep = "\x1f"
+
+func (e extEntry) FilterValue() string {
+	return strings.Join([]string{e.FullName, e.description, strings.Join(e.Topics, " "), e.Readme}, filterFieldSep)
+}
+
+// filterFieldIndex identifies the position of each field packed into
+// FilterValue() above.
+const (
+	filterFieldName = iota
+	filterFieldDescription
+	filterFieldTopics
+	filterFieldReadme
+)
+
+// extFilter is a list.FilterFunc that fuzzy-ranks extEntry values across
+// their name, description, topics and cached README rather than doing a
+// plain substring match. A leading "/readme " restricts matching to README
+// content only, and a "topic:foo" qualifier (anywhere in the term) requires
+// an exact topic match before fuzzy-ranking the rest of the query.
+func extFilter(term string, targets []string) []list.Rank {
+	readmeOnly := false
+	if rest, ok := cutPrefix(term, "/readme "); ok {
+		readmeOnly = true
+		term = rest
+	}
+
+	var topic string
+	var queryWords []string
+	for _, f := range strings.Fields(term) {
+		if t, ok := cutPrefix(f, "topic:"); ok {
+			topic = t
+			continue
+		}
+		queryWords = append(queryWords, f)
+	}
+	query := strings.Join(queryWords, " ")
+
+	type candidate struct {
+		index  int
+		source string
+	}
+	candidates := make([]candidate, 0, len(targets))
+	for i, t := range targets {
+		fields := strings.SplitN(t, filterFieldSep, 4)
+		for len(fields) < 4 {
+			fields = append(fields, "")
+		}
+		if topic != "" && !hasExactTopic(fields[filterFieldTopics], topic) {
+			continue
+		}
+		source := fields[filterFieldReadme]
+		if !readmeOnly {
+			source = fields[filterFieldName] + " " + fields[filterFieldDescription] + " " + fields[filterFieldTopics] + " " + source
+		}
+		candidates = append(candidates, candidate{index: i, source: source})
+	}
+
+	if query == "" {
+		ranks := make([]list.Rank, len(candidates))
+		for i, c := range candidates {
+			ranks[i] = list.Rank{Index: c.index}
+		}
+		return ranks
+	}
+
+	sources := make([]string, len(candidates))
+	for i, c := range candidates {
+		sources[i] = c.source
+	}
+
+	found := fuzzy.Find(query, sources)
+	ranks := make([]list.Rank, 0, len(found))
+	for _, match := range found {
+		cand := candidates[match.Index]
+		nameFieldLen := len(strings.SplitN(targets[cand.index], filterFieldSep, 4)[filterFieldName])
+		var titleMatches []int
+		if !readmeOnly {
+			for _, idx := range match.MatchedIndexes {
+				if idx < nameFieldLen {
+					titleMatches = append(titleMatches, idx)
+				}
+			}
+		}
+		ranks = append(ranks, list.Rank{
+			Index:          cand.index,
+			MatchedIndexes: titleMatches,
+		})
+	}
+	return ranks
+}
+
+// hasExactTopic reports whether topic (case-insensitively) exactly matches
+// one of the space-separated topics packed into a FilterValue() field,
+// rather than merely appearing as a substring of one.
+func hasExactTopic(topics, topic string) bool {
+	topic = strings.ToLower(topic)
+	for _, t := range strings.Fields(topics) {
+		if strings.ToLower(t) == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return strings.TrimPrefix(s, prefix), true
+}
 
 type keyMap struct {
-	install key.Binding
-	remove  key.Binding
-	web     key.Binding
+	install     key.Binding
+	remove      key.Binding
+	web         key.Binding
+	sortPopover key.Binding
 }
 
 func newKeyMap() *keyMap {
@@ -258,15 +574,232 @@ func newKeyMap() *keyMap {
 			key.WithKeys("w"),
 			key.WithHelp("w", "open on web"),
 		),
+		sortPopover: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort & filter"),
+		),
+	}
+}
+
+// sortOrder is a persisted, user-selectable ordering for the extension list.
+type sortOrder int
+
+const (
+	sortByStars sortOrder = iota
+	sortByUpdated
+	sortByName
+)
+
+func (s sortOrder) String() string {
+	switch s {
+	case sortByUpdated:
+		return "updated"
+	case sortByName:
+		return "name"
+	default:
+		return "stars"
+	}
+}
+
+func parseSortOrder(s string) sortOrder {
+	switch s {
+	case "updated":
+		return sortByUpdated
+	case "name":
+		return sortByName
+	default:
+		return sortByStars
+	}
+}
+
+// filterState is the list's current sort order and narrowing filters. It is
+// persisted in the gh config so it survives across `gh ext browse` runs.
+type filterState struct {
+	sort          sortOrder
+	installedOnly bool
+	officialOnly  bool
+	hasReadmeOnly bool
+	language      string
+}
+
+const (
+	cfgKeyBrowseSort      = "extension_browse_sort"
+	cfgKeyBrowseInstalled = "extension_browse_installed_only"
+	cfgKeyBrowseOfficial  = "extension_browse_official_only"
+	cfgKeyBrowseHasReadme = "extension_browse_has_readme"
+	cfgKeyBrowseLanguage  = "extension_browse_language"
+)
+
+func loadFilterState(cfg config.Config) filterState {
+	var fs filterState
+	if cfg == nil {
+		return fs
+	}
+	if v, err := cfg.Get("", cfgKeyBrowseSort); err == nil {
+		fs.sort = parseSortOrder(v)
+	}
+	if v, err := cfg.Get("", cfgKeyBrowseInstalled); err == nil {
+		fs.installedOnly = v == "true"
+	}
+	if v, err := cfg.Get("", cfgKeyBrowseOfficial); err == nil {
+		fs.officialOnly = v == "true"
+	}
+	if v, err := cfg.Get("", cfgKeyBrowseHasReadme); err == nil {
+		fs.hasReadmeOnly = v == "true"
+	}
+	if v, err := cfg.Get("", cfgKeyBrowseLanguage); err == nil {
+		fs.language = v
+	}
+	return fs
+}
+
+func (fs filterState) save(cfg config.Config, logger *log.Logger) {
+	if cfg == nil {
+		return
+	}
+	cfg.Set("", cfgKeyBrowseSort, fs.sort.String())
+	cfg.Set("", cfgKeyBrowseInstalled, strconv.FormatBool(fs.installedOnly))
+	cfg.Set("", cfgKeyBrowseOfficial, strconv.FormatBool(fs.officialOnly))
+	cfg.Set("", cfgKeyBrowseHasReadme, strconv.FormatBool(fs.hasReadmeOnly))
+	cfg.Set("", cfgKeyBrowseLanguage, fs.language)
+	if err := cfg.Write(); err != nil {
+		logger.Printf("failed to persist browse sort/filter state: %s", err.Error())
 	}
 }
 
+// popoverItem is one selectable row of the sort & filter popover.
+type popoverItem int
+
+const (
+	popoverSortStars popoverItem = iota
+	popoverSortUpdated
+	popoverSortName
+	popoverInstalledOnly
+	popoverOfficialOnly
+	popoverHasReadme
+	popoverLanguage
+)
+
+var popoverItems = []popoverItem{
+	popoverSortStars,
+	popoverSortUpdated,
+	popoverSortName,
+	popoverInstalledOnly,
+	popoverOfficialOnly,
+	popoverHasReadme,
+	popoverLanguage,
+}
+
+func (p popoverItem) apply(fs *filterState, languages []string) {
+	switch p {
+	case popoverSortStars:
+		fs.sort = sortByStars
+	case popoverSortUpdated:
+		fs.sort = sortByUpdated
+	case popoverSortName:
+		fs.sort = sortByName
+	case popoverInstalledOnly:
+		fs.installedOnly = !fs.installedOnly
+	case popoverOfficialOnly:
+		fs.officialOnly = !fs.officialOnly
+	case popoverHasReadme:
+		fs.hasReadmeOnly = !fs.hasReadmeOnly
+	case popoverLanguage:
+		fs.language = nextLanguage(fs.language, languages)
+	}
+}
+
+func (p popoverItem) label(fs filterState) string {
+	radio := func(on bool) string {
+		if on {
+			return "(x)"
+		}
+		return "( )"
+	}
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	switch p {
+	case popoverSortStars:
+		return radio(fs.sort == sortByStars) + " sort: stars"
+	case popoverSortUpdated:
+		return radio(fs.sort == sortByUpdated) + " sort: recently updated"
+	case popoverSortName:
+		return radio(fs.sort == sortByName) + " sort: name"
+	case popoverInstalledOnly:
+		return check(fs.installedOnly) + " installed only"
+	case popoverOfficialOnly:
+		return check(fs.officialOnly) + " official only"
+	case popoverHasReadme:
+		return check(fs.hasReadmeOnly) + " has readme"
+	case popoverLanguage:
+		lang := fs.language
+		if lang == "" {
+			lang = "any"
+		}
+		return "    language: " + lang
+	default:
+		return ""
+	}
+}
+
+func nextLanguage(current string, languages []string) string {
+	if len(languages) == 0 {
+		return ""
+	}
+	if current == "" {
+		return languages[0]
+	}
+	for i, l := range languages {
+		if l == current {
+			if i+1 < len(languages) {
+				return languages[i+1]
+			}
+			return ""
+		}
+	}
+	return languages[0]
+}
+
+// sortPopoverModel is a small lipgloss-rendered popover, opened with the "s"
+// key, for picking sortOrder and toggling filterState's narrowing filters.
+type sortPopoverModel struct {
+	cursor    int
+	languages []string
+}
+
+func newSortPopoverModel(languages []string) *sortPopoverModel {
+	return &sortPopoverModel{languages: languages}
+}
+
+func (p *sortPopoverModel) View(fs filterState) string {
+	box := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	var b strings.Builder
+	b.WriteString("sort & filter  (enter: apply/toggle, esc: close)\n")
+	for i, item := range popoverItems {
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+		b.WriteString(cursor + item.label(fs) + "\n")
+	}
+	return box.Render(strings.TrimRight(b.String(), "\n"))
+}
+
 type extListModel struct {
-	list    list.Model
-	keys    *keyMap
-	manager tea.Model
-	logger  *log.Logger
-	browser ibrowser
+	list          list.Model
+	keys          *keyMap
+	manager       tea.Model
+	logger        *log.Logger
+	browser       ibrowser
+	confirmRemove *extEntry
+	entries       []extEntry
+	filter        filterState
+	cfg           config.Config
+	popover       *sortPopoverModel
 }
 
 func newExtListModel(opts extBrowseOpts, extEntries []extEntry) extListModel {
@@ -275,6 +808,7 @@ func newExtListModel(opts extBrowseOpts, extEntries []extEntry) extListModel {
 		items[i] = extEntries[i]
 	}
 	list := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	list.Filter = extFilter
 
 	keys := newKeyMap()
 	list.Title = "gh extensions"
@@ -283,16 +817,110 @@ func newExtListModel(opts extBrowseOpts, extEntries []extEntry) extListModel {
 			keys.remove,
 			keys.install,
 			keys.web,
+			keys.sortPopover,
 		}
 	}
 
-	return extListModel{
+	m := extListModel{
 		logger:  opts.logger,
-		manager: newManagerModel(opts.logger),
+		manager: newManagerModel(opts.logger, opts.em),
 		list:    list,
 		keys:    keys,
 		browser: opts.browser,
+		entries: extEntries,
+		filter:  loadFilterState(opts.cfg),
+		cfg:     opts.cfg,
+	}
+	m.applyView()
+	return m
+}
+
+// languages returns the sorted, de-duplicated set of languages present
+// across the master entry list, used to drive the popover's language cycle.
+func (m extListModel) languages() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, ee := range m.entries {
+		if ee.Language == "" || seen[ee.Language] {
+			continue
+		}
+		seen[ee.Language] = true
+		out = append(out, ee.Language)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Neighbors returns the current selection together with up to n visible
+// items above and below it, for speculative README prefetching.
+func (m extListModel) Neighbors(n int) []extEntry {
+	visible := m.list.VisibleItems()
+	idx := m.list.Index()
+
+	var out []extEntry
+	for d := -n; d <= n; d++ {
+		i := idx + d
+		if i < 0 || i >= len(visible) {
+			continue
+		}
+		out = append(out, visible[i].(extEntry))
+	}
+	return out
+}
+
+// SetReadme records the fetched README body on the matching master entry and
+// rebuilds the visible list so FilterValue() (and the `/readme` filter
+// prefix) can match against it.
+func (m *extListModel) SetReadme(fullName, readme string) {
+	changed := false
+	for i, ee := range m.entries {
+		if ee.FullName == fullName && (ee.Readme != readme || !ee.ReadmeFetched) {
+			m.entries[i].Readme = readme
+			m.entries[i].ReadmeFetched = true
+			changed = true
+		}
+	}
+	if changed {
+		m.applyView()
+	}
+}
+
+// applyView deterministically rebuilds the visible list items from the
+// master entries slice according to the current filterState.
+func (m *extListModel) applyView() {
+	filtered := make([]extEntry, 0, len(m.entries))
+	for _, ee := range m.entries {
+		if m.filter.installedOnly && !ee.Installed {
+			continue
+		}
+		if m.filter.officialOnly && !ee.Official {
+			continue
+		}
+		if m.filter.hasReadmeOnly && ee.ReadmeFetched && ee.Readme == "" {
+			continue
+		}
+		if m.filter.language != "" && ee.Language != m.filter.language {
+			continue
+		}
+		filtered = append(filtered, ee)
+	}
+
+	switch m.filter.sort {
+	case sortByUpdated:
+		sort.Slice(filtered, func(i, j int) bool { return filtered[i].UpdatedAt.After(filtered[j].UpdatedAt) })
+	case sortByName:
+		sort.Slice(filtered, func(i, j int) bool {
+			return strings.ToLower(filtered[i].FullName) < strings.ToLower(filtered[j].FullName)
+		})
+	default:
+		sort.Slice(filtered, func(i, j int) bool { return filtered[i].Stars > filtered[j].Stars })
+	}
+
+	items := make([]list.Item, len(filtered))
+	for i, ee := range filtered {
+		items[i] = ee
 	}
+	m.list.SetItems(items)
 }
 
 func (m extListModel) Init() tea.Cmd {
@@ -300,15 +928,61 @@ func (m extListModel) Init() tea.Cmd {
 }
 
 func (m extListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		_, h := appStyle.GetFrameSize()
 		m.list.SetSize(msg.Width-100, msg.Height-h)
+	case progressMsg:
+		if msg.done && msg.err == nil {
+			for i, ee := range m.entries {
+				if ee.FullName == msg.repoFullName {
+					m.entries[i].Installed = msg.action == "install"
+				}
+			}
+			m.applyView()
+		}
 	case tea.KeyMsg:
+		if m.popover != nil {
+			switch msg.String() {
+			case "up", "k":
+				if m.popover.cursor > 0 {
+					m.popover.cursor--
+				}
+			case "down", "j":
+				if m.popover.cursor < len(popoverItems)-1 {
+					m.popover.cursor++
+				}
+			case "enter":
+				popoverItems[m.popover.cursor].apply(&m.filter, m.popover.languages)
+				m.applyView()
+			case "esc", "q", "s":
+				m.popover = nil
+				m.filter.save(m.cfg, m.logger)
+			}
+			return m, nil
+		}
 		if m.list.FilterState() == list.Filtering {
 			break
 		}
+		if m.confirmRemove != nil {
+			target := *m.confirmRemove
+			m.confirmRemove = nil
+			if msg.String() == "y" {
+				cmds = append(cmds, m.manager.(managerModel).Remove(target))
+			}
+			break
+		}
 		item := m.SelectedItem()
+		if item == nil {
+			// The filtered/sorted view can be empty (e.g. a filter matches
+			// nothing); nothing here applies to a missing selection.
+			if key.Matches(msg, m.keys.sortPopover) {
+				m.popover = newSortPopoverModel(m.languages())
+			}
+			break
+		}
 		ee := item.(extEntry)
 		switch {
 		case key.Matches(msg, m.keys.web):
@@ -316,20 +990,21 @@ func (m extListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.logger.Printf("failed to open '%s': %s", ee.URL, err.Error())
 			}
 		case key.Matches(msg, m.keys.install):
-			m.manager.(managerModel).Install(ee.FullName)
+			cmds = append(cmds, m.manager.(managerModel).Install(ee))
 		case key.Matches(msg, m.keys.remove):
-			panic("REMOVE!")
+			m.confirmRemove = &ee
+		case key.Matches(msg, m.keys.sortPopover):
+			m.popover = newSortPopoverModel(m.languages())
 		}
 	}
 
-	var cmds []tea.Cmd
-
 	nlm, cmd := m.list.Update(msg)
 	m.list = nlm
 	cmds = append(cmds, cmd)
 
 	nfm, cmd := m.manager.Update(msg)
 	m.manager = nfm
+	cmds = append(cmds, cmd)
 
 	return m, tea.Batch(cmds...)
 }
@@ -340,7 +1015,14 @@ func (m extListModel) SelectedItem() list.Item {
 }
 
 func (m extListModel) View() string {
-	return appStyle.Render(m.list.View()) + "\n" + m.manager.View()
+	view := appStyle.Render(m.list.View()) + "\n" + m.manager.View()
+	if m.confirmRemove != nil {
+		view += fmt.Sprintf("\nremove %s? (y/N)", m.confirmRemove.FullName)
+	}
+	if m.popover != nil {
+		view += "\n" + m.popover.View(m.filter)
+	}
+	return view
 }
 
 type ibrowser interface {
@@ -356,6 +1038,54 @@ type extBrowseOpts struct {
 	logger   *log.Logger
 	cfg      config.Config
 	rg       readmeGetter
+	// Refresh forces revalidation of the on-disk README cache, bypassing
+	// the TTL. Bound to the browse command's `--refresh` flag.
+	Refresh bool
+}
+
+// NewCmdBrowse returns a command that launches the interactive extension
+// browser. runF is overridden in tests to stub out the TUI entirely.
+func NewCmdBrowse(f *cmdutil.Factory, runF func(extBrowseOpts) error) *cobra.Command {
+	opts := extBrowseOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "browse",
+		Short: "Enter a UI for browsing, adding, and removing extensions",
+		Long: heredoc.Doc(`
+			This command will take over your terminal and run a TUI for browsing, adding,
+			and removing gh extensions. Running it will cache information about available
+			extensions for up to 24 hours.
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			httpClient, err := f.HttpClient()
+			if err != nil {
+				return err
+			}
+
+			cfg, err := f.Config()
+			if err != nil {
+				return err
+			}
+
+			host, _ := cfg.DefaultHost()
+
+			opts.cmd = cmd
+			opts.cfg = cfg
+			opts.client = httpClient
+			opts.em = f.ExtensionManager
+			opts.browser = f.Browser
+			opts.searcher = search.NewSearcher(httpClient, host)
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return extBrowse(opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Refresh, "refresh", false, "Refresh cached extension data")
+
+	return cmd
 }
 
 func extBrowse(opts extBrowseOpts) error {
@@ -369,7 +1099,6 @@ func extBrowse(opts extBrowseOpts) error {
 	opts.logger = log.New(f, "", log.Lshortfile)
 
 	// TODO spinner
-	// TODO get manager to tell me what's installed so I can cross ref
 	installed := opts.em.List()
 
 	result, err := opts.searcher.Repositories(search.Query{
@@ -394,6 +1123,9 @@ func extBrowse(opts extBrowseOpts) error {
 			Owner:       repo.Owner.Login,
 			Name:        repo.Name,
 			Stars:       repo.StargazersCount,
+			Topics:      repo.Topics,
+			UpdatedAt:   repo.UpdatedAt,
+			Language:    repo.Language,
 			description: repo.Description,
 		}
 		for _, v := range installed {
@@ -415,7 +1147,7 @@ func extBrowse(opts extBrowseOpts) error {
 		extEntries = append(extEntries, ee)
 	}
 
-	opts.rg = newReadmeGetter(opts.client)
+	opts.rg = newReadmeGetter(opts.client, host, config.ConfigDir(), opts.Refresh)
 
 	return tea.NewProgram(newUIModel(opts, extEntries)).Start()
-}
\ No newline at end of file
+}
@@ -0,0 +1,96 @@
+package extension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func namesOf(m extListModel) []string {
+	var out []string
+	for _, item := range m.list.Items() {
+		out = append(out, item.(extEntry).FullName)
+	}
+	return out
+}
+
+func TestApplyViewFilters(t *testing.T) {
+	entries := []extEntry{
+		{FullName: "cli/gh-installed", Installed: true, Stars: 1},
+		{FullName: "cli/gh-official", Official: true, Stars: 2},
+		{FullName: "cli/gh-has-readme", Readme: "hi", ReadmeFetched: true, Stars: 3},
+		{FullName: "cli/gh-no-readme", ReadmeFetched: true, Stars: 4},
+		{FullName: "cli/gh-unfetched", Language: "Go", Stars: 5},
+	}
+
+	tests := []struct {
+		name   string
+		filter filterState
+		want   []string
+	}{
+		{
+			name:   "no filters",
+			filter: filterState{},
+			want:   []string{"cli/gh-unfetched", "cli/gh-no-readme", "cli/gh-has-readme", "cli/gh-official", "cli/gh-installed"},
+		},
+		{
+			name:   "installed only",
+			filter: filterState{installedOnly: true},
+			want:   []string{"cli/gh-installed"},
+		},
+		{
+			name:   "official only",
+			filter: filterState{officialOnly: true},
+			want:   []string{"cli/gh-official"},
+		},
+		{
+			name:   "language filter",
+			filter: filterState{language: "Go"},
+			want:   []string{"cli/gh-unfetched"},
+		},
+		{
+			name:   "has readme only excludes confirmed-empty but not unfetched",
+			filter: filterState{hasReadmeOnly: true},
+			want:   []string{"cli/gh-unfetched", "cli/gh-has-readme", "cli/gh-official", "cli/gh-installed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := extListModel{entries: entries, filter: tt.filter}
+			m.list = list.New(nil, list.NewDefaultDelegate(), 0, 0)
+			m.applyView()
+			assert.Equal(t, tt.want, namesOf(m))
+		})
+	}
+}
+
+func TestApplyViewSortOrder(t *testing.T) {
+	now := time.Now()
+	entries := []extEntry{
+		{FullName: "cli/gh-zeta", Stars: 10, UpdatedAt: now.Add(-2 * time.Hour)},
+		{FullName: "cli/gh-alpha", Stars: 1, UpdatedAt: now},
+		{FullName: "cli/gh-mid", Stars: 5, UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name string
+		sort sortOrder
+		want []string
+	}{
+		{name: "by stars", sort: sortByStars, want: []string{"cli/gh-zeta", "cli/gh-mid", "cli/gh-alpha"}},
+		{name: "by updated", sort: sortByUpdated, want: []string{"cli/gh-alpha", "cli/gh-mid", "cli/gh-zeta"}},
+		{name: "by name", sort: sortByName, want: []string{"cli/gh-alpha", "cli/gh-mid", "cli/gh-zeta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := extListModel{entries: entries, filter: filterState{sort: tt.sort}}
+			m.list = list.New(nil, list.NewDefaultDelegate(), 0, 0)
+			m.applyView()
+			assert.Equal(t, tt.want, namesOf(m))
+		})
+	}
+}
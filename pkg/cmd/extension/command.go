@@ -0,0 +1,20 @@
+package extension
+
+import (
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdExtension returns the parent `gh extension` command, wiring up its
+// subcommands.
+func NewCmdExtension(f *cmdutil.Factory) *cobra.Command {
+	extCmd := &cobra.Command{
+		Use:     "extension",
+		Short:   "Manage gh extensions",
+		Aliases: []string{"extensions", "ext"},
+	}
+
+	extCmd.AddCommand(NewCmdBrowse(f, nil))
+
+	return extCmd
+}
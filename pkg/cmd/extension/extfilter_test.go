@@ -0,0 +1,76 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/stretchr/testify/assert"
+)
+
+func filterValues(entries []extEntry) []string {
+	targets := make([]string, len(entries))
+	for i, ee := range entries {
+		targets[i] = ee.FilterValue()
+	}
+	return targets
+}
+
+func rankedNames(entries []extEntry, ranks []list.Rank) []string {
+	names := make([]string, len(ranks))
+	for i, r := range ranks {
+		names[i] = entries[r.Index].FullName
+	}
+	return names
+}
+
+func TestExtFilterReadmePrefix(t *testing.T) {
+	entries := []extEntry{
+		{FullName: "cli/gh-one", description: "widgets", Readme: "nothing relevant here"},
+		{FullName: "cli/gh-two", description: "gadgets", Readme: "# Installation\nsteps go here"},
+	}
+	targets := filterValues(entries)
+
+	// Without the /readme prefix, the README is searched alongside the name
+	// and description, so "Installation" still matches gh-two's README.
+	ranks := extFilter("Installation", targets)
+	assert.Equal(t, []string{"cli/gh-two"}, rankedNames(entries, ranks))
+
+	// With the /readme prefix, only the README content is searched.
+	ranks = extFilter("/readme Installation", targets)
+	assert.Equal(t, []string{"cli/gh-two"}, rankedNames(entries, ranks))
+}
+
+func TestExtFilterTopicQualifier(t *testing.T) {
+	entries := []extEntry{
+		{FullName: "cli/gh-one", Topics: []string{"productivity"}},
+		{FullName: "cli/gh-two", Topics: []string{"gh-extension", "productivity"}},
+	}
+	targets := filterValues(entries)
+
+	ranks := extFilter("topic:gh-extension", targets)
+	assert.Equal(t, []string{"cli/gh-two"}, rankedNames(entries, ranks))
+}
+
+func TestExtFilterTopicQualifierIsExactNotSubstring(t *testing.T) {
+	entries := []extEntry{
+		{FullName: "cli/gh-js", Topics: []string{"javascript"}},
+		{FullName: "cli/gh-script", Topics: []string{"script"}},
+	}
+	targets := filterValues(entries)
+
+	// "script" must not match the "javascript" topic just because it's a
+	// substring of it.
+	ranks := extFilter("topic:script", targets)
+	assert.Equal(t, []string{"cli/gh-script"}, rankedNames(entries, ranks))
+}
+
+func TestExtFilterEmptyQueryReturnsAll(t *testing.T) {
+	entries := []extEntry{
+		{FullName: "cli/gh-one"},
+		{FullName: "cli/gh-two"},
+	}
+	targets := filterValues(entries)
+
+	ranks := extFilter("", targets)
+	assert.ElementsMatch(t, []string{"cli/gh-one", "cli/gh-two"}, rankedNames(entries, ranks))
+}
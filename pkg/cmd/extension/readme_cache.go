@@ -0,0 +1,168 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultReadmeCacheTTL is how long a cached README is considered fresh
+// before a conditional revalidation request is made.
+const defaultReadmeCacheTTL = 24 * time.Hour
+
+// maxCachedReadmes bounds the on-disk README cache so browsing the full
+// extension list repeatedly doesn't grow it without limit. When exceeded,
+// the least recently used entries are evicted.
+const maxCachedReadmes = 500
+
+// readmeCacheMeta is the sidecar JSON recorded next to each cached README,
+// used to make conditional requests and judge staleness.
+type readmeCacheMeta struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// diskReadmeCache stores rendered-free README markdown on disk under the gh
+// config directory, keyed by repo full name, alongside a metadata sidecar
+// that enables conditional GETs.
+type diskReadmeCache struct {
+	dir string
+	ttl time.Duration
+}
+
+func newDiskReadmeCache(dir string, ttl time.Duration) *diskReadmeCache {
+	if ttl <= 0 {
+		ttl = defaultReadmeCacheTTL
+	}
+	return &diskReadmeCache{dir: dir, ttl: ttl}
+}
+
+func (c *diskReadmeCache) paths(repoFullName string) (content string, meta string) {
+	parts := strings.SplitN(repoFullName, "/", 2)
+	owner, name := parts[0], ""
+	if len(parts) == 2 {
+		name = parts[1]
+	}
+	base := filepath.Join(c.dir, owner, name)
+	return base + ".md", base + ".meta.json"
+}
+
+// Load reads a cached README and its metadata, if present. fresh reports
+// whether the entry is still within the TTL and callers can skip revalidation
+// entirely.
+func (c *diskReadmeCache) Load(repoFullName string) (content string, meta readmeCacheMeta, fresh bool, ok bool) {
+	contentPath, metaPath := c.paths(repoFullName)
+
+	rawContent, err := os.ReadFile(contentPath)
+	if err != nil {
+		return "", readmeCacheMeta{}, false, false
+	}
+	rawMeta, err := os.ReadFile(metaPath)
+	if err != nil {
+		return "", readmeCacheMeta{}, false, false
+	}
+	if err := json.Unmarshal(rawMeta, &meta); err != nil {
+		return "", readmeCacheMeta{}, false, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(contentPath, now, now) // mark as recently used for LRU eviction
+
+	return string(rawContent), meta, time.Since(meta.FetchedAt) < c.ttl, true
+}
+
+// Save writes content and its metadata to disk, then evicts the least
+// recently used entries if the cache has grown past maxCachedReadmes.
+func (c *diskReadmeCache) Save(repoFullName, content string, meta readmeCacheMeta) error {
+	contentPath, metaPath := c.paths(repoFullName)
+	if err := os.MkdirAll(filepath.Dir(contentPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(contentPath, []byte(content), 0600); err != nil {
+		return err
+	}
+	rawMeta, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(metaPath, rawMeta, 0600); err != nil {
+		return err
+	}
+	return c.evict()
+}
+
+func (c *diskReadmeCache) evict() error {
+	var entries []struct {
+		path    string
+		modTime time.Time
+	}
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		entries = append(entries, struct {
+			path    string
+			modTime time.Time
+		}{path, info.ModTime()})
+		return nil
+	})
+	if err != nil || len(entries) <= maxCachedReadmes {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, e := range entries[:len(entries)-maxCachedReadmes] {
+		_ = os.Remove(e.path)
+		_ = os.Remove(strings.TrimSuffix(e.path, ".md") + ".meta.json")
+	}
+	return nil
+}
+
+// conditionalFetch performs a GET against url, sending If-None-Match /
+// If-Modified-Since headers from meta when present. A 304 response reports
+// notModified so the caller can keep using its cached content.
+func conditionalFetch(client *http.Client, url string, meta readmeCacheMeta) (body []byte, newMeta readmeCacheMeta, notModified bool, err error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, readmeCacheMeta{}, false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw+json")
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, readmeCacheMeta{}, false, err
+	}
+	defer resp.Body.Close()
+
+	newMeta = readmeCacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, newMeta, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, readmeCacheMeta{}, false, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, readmeCacheMeta{}, false, err
+	}
+	return body, newMeta, false, nil
+}
@@ -0,0 +1,94 @@
+package extension
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskReadmeCacheSaveAndLoad(t *testing.T) {
+	c := newDiskReadmeCache(t.TempDir(), time.Hour)
+
+	_, _, _, ok := c.Load("cli/gh-test")
+	assert.False(t, ok, "expected cache miss before Save")
+
+	meta := readmeCacheMeta{ETag: `"abc123"`, FetchedAt: time.Now()}
+	require.NoError(t, c.Save("cli/gh-test", "# hello", meta))
+
+	content, gotMeta, fresh, ok := c.Load("cli/gh-test")
+	require.True(t, ok)
+	assert.Equal(t, "# hello", content)
+	assert.Equal(t, meta.ETag, gotMeta.ETag)
+	assert.True(t, fresh)
+}
+
+func TestDiskReadmeCacheExpires(t *testing.T) {
+	c := newDiskReadmeCache(t.TempDir(), time.Millisecond)
+
+	meta := readmeCacheMeta{FetchedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, c.Save("cli/gh-test", "# hello", meta))
+
+	content, _, fresh, ok := c.Load("cli/gh-test")
+	require.True(t, ok)
+	assert.Equal(t, "# hello", content)
+	assert.False(t, fresh, "entry older than the ttl should not be fresh")
+}
+
+func TestDiskReadmeCacheEvictsPastLimit(t *testing.T) {
+	dir := t.TempDir()
+	c := newDiskReadmeCache(dir, time.Hour)
+
+	const extra = 5
+	for i := 0; i < maxCachedReadmes+extra; i++ {
+		repo := fmt.Sprintf("owner/repo-%04d", i)
+		require.NoError(t, c.Save(repo, "content", readmeCacheMeta{FetchedAt: time.Now()}))
+		// Ensure distinct mtimes so eviction order is deterministic.
+		time.Sleep(time.Millisecond)
+	}
+
+	var mdFiles int
+	require.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		if !info.IsDir() && filepath.Ext(path) == ".md" {
+			mdFiles++
+		}
+		return nil
+	}))
+	assert.LessOrEqual(t, mdFiles, maxCachedReadmes)
+}
+
+func TestConditionalFetch(t *testing.T) {
+	const etag = `"v1"`
+	var reqCount int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCount++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("# readme"))
+	}))
+	defer ts.Close()
+
+	client := ts.Client()
+
+	body, meta, notModified, err := conditionalFetch(client, ts.URL, readmeCacheMeta{})
+	require.NoError(t, err)
+	assert.False(t, notModified)
+	assert.Equal(t, "# readme", string(body))
+	assert.Equal(t, etag, meta.ETag)
+
+	_, _, notModified, err = conditionalFetch(client, ts.URL, meta)
+	require.NoError(t, err)
+	assert.True(t, notModified, "server should report 304 when the ETag matches")
+	assert.Equal(t, 2, reqCount)
+}
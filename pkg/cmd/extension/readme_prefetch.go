@@ -0,0 +1,130 @@
+package extension
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+// readmePrefetchWorkers bounds how many READMEs are fetched and rendered
+// concurrently, regardless of how many entries are queued as the cursor
+// moves around the list.
+const readmePrefetchWorkers = 4
+
+// readmePrefetchRadius is how many items above and below the current
+// selection get prefetched, so arrow-key navigation rarely has to wait on a
+// fetch.
+const readmePrefetchRadius = 3
+
+// readmeReadyMsg reports a finished (fullName, width) render, whether it was
+// requested for the current selection or prefetched speculatively for a
+// neighboring item. readme is the raw markdown (cached on the list item for
+// filtering); rendered is the glamour output (cached for display).
+type readmeReadyMsg struct {
+	fullName string
+	width    int
+	readme   string
+	rendered string
+	err      error
+}
+
+type readmeJob struct {
+	fullName string
+	width    int
+}
+
+// readmePrefetcher renders READMEs (fetch + glamour render) on a small fixed
+// worker pool and reports results over a channel, so bursts of selection
+// changes (e.g. holding an arrow key) can't spawn unbounded goroutines.
+type readmePrefetcher struct {
+	getter  readmeGetter
+	logger  *log.Logger
+	jobs    chan readmeJob
+	results chan readmeReadyMsg
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+}
+
+func newReadmePrefetcher(getter readmeGetter, logger *log.Logger) *readmePrefetcher {
+	p := &readmePrefetcher{
+		getter:   getter,
+		logger:   logger,
+		jobs:     make(chan readmeJob, 64),
+		results:  make(chan readmeReadyMsg, 64),
+		inFlight: map[string]bool{},
+	}
+	for i := 0; i < readmePrefetchWorkers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func readmeJobKey(fullName string, width int) string {
+	return fmt.Sprintf("%s@%d", fullName, width)
+}
+
+// enqueue schedules a render for (fullName, width) unless one is already
+// queued or in progress. It never blocks the caller: a full queue just drops
+// the request, and the next selection change or resize will retry it.
+func (p *readmePrefetcher) enqueue(fullName string, width int) {
+	key := readmeJobKey(fullName, width)
+
+	p.mu.Lock()
+	if p.inFlight[key] {
+		p.mu.Unlock()
+		return
+	}
+	p.inFlight[key] = true
+	p.mu.Unlock()
+
+	select {
+	case p.jobs <- readmeJob{fullName: fullName, width: width}:
+	default:
+		p.mu.Lock()
+		delete(p.inFlight, key)
+		p.mu.Unlock()
+	}
+}
+
+func (p *readmePrefetcher) work() {
+	for job := range p.jobs {
+		readme, rendered, err := p.render(job.fullName, job.width)
+
+		p.mu.Lock()
+		delete(p.inFlight, readmeJobKey(job.fullName, job.width))
+		p.mu.Unlock()
+
+		p.results <- readmeReadyMsg{fullName: job.fullName, width: job.width, readme: readme, rendered: rendered, err: err}
+	}
+}
+
+func (p *readmePrefetcher) render(fullName string, width int) (readme string, rendered string, err error) {
+	readme, err = p.getter.Get(fullName)
+	if err != nil {
+		return "", "", err
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithAutoStyle(),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return readme, "", err
+	}
+
+	rendered, err = renderer.Render(readme)
+	return readme, rendered, err
+}
+
+// waitForResult returns a tea.Cmd that blocks for the next finished render.
+// The caller must re-issue it after every readmeReadyMsg to keep draining
+// the results channel.
+func (p *readmePrefetcher) waitForResult() tea.Cmd {
+	return func() tea.Msg {
+		return <-p.results
+	}
+}